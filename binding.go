@@ -0,0 +1,124 @@
+package tranquility
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Binder populates in from parts of an *http.Request other than its body:
+// path values, query parameters, and headers. WithBinder lets you swap in a
+// different implementation (e.g. go-playground's decoder or Gorilla schema);
+// the default is defaultBinder, which reads the `tranquility:"source,name"`
+// struct tag documented on Handler
+type Binder interface {
+	Bind(r *http.Request, in any) error
+}
+
+// defaultBinder implements Binder using the `tranquility:"path,id"`,
+// `tranquility:"query,limit"`, and `tranquility:"header,X-Request-ID"`
+// struct tags. Fields tagged `tranquility:"body"` (or left untagged) are left
+// alone here; they're filled afterward by the Handler's body unmarshal.
+// String, int, uint, float, and bool fields are supported; a tagged field of
+// any other kind, or a value that fails to parse for its field's kind,
+// produces an error rather than a silently zero-valued field
+type defaultBinder struct{}
+
+func (defaultBinder) Bind(r *http.Request, in any) error {
+	v := reflect.ValueOf(in)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+
+	v = v.Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		tag := t.Field(i).Tag.Get("tranquility")
+		if tag == "" {
+			continue
+		}
+
+		source, name, ok := strings.Cut(tag, ",")
+		if !ok {
+			continue
+		}
+
+		var value string
+
+		switch source {
+		case "path":
+			value = r.PathValue(name)
+		case "query":
+			value = r.URL.Query().Get(name)
+		case "header":
+			value = r.Header.Get(name)
+		default:
+			continue
+		}
+
+		if value == "" {
+			continue
+		}
+
+		supported, err := setFieldValue(field, value)
+		if err != nil {
+			if !supported {
+				return fmt.Errorf("tranquility: binding %s %q into field %s: %w", source, name, t.Field(i).Name, err)
+			}
+
+			return InvalidParams{Params: []string{name}, Message: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// setFieldValue parses value into field according to field's kind. supported
+// reports whether field's kind is one defaultBinder knows how to fill at
+// all; a caller should treat an unsupported kind as a binder misconfiguration
+// rather than bad client input
+func setFieldValue(field reflect.Value, value string) (supported bool, err error) {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+		return true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return true, err
+		}
+		field.SetInt(n)
+		return true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return true, err
+		}
+		field.SetUint(n)
+		return true, nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return true, err
+		}
+		field.SetFloat(n)
+		return true, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return true, err
+		}
+		field.SetBool(b)
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported binder field kind %s", field.Kind())
+	}
+}
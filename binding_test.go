@@ -0,0 +1,112 @@
+package tranquility_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syke99/tranquility"
+)
+
+type ListParams struct {
+	ID    string `tranquility:"path,id"`
+	Limit string `tranquility:"query,limit"`
+}
+
+var TestBindHandler = func(ctx context.Context, in *ListParams) (*Buzz, error) {
+	return &Buzz{Greeting: in.ID + ":" + in.Limit}, nil
+}
+
+type PageParams struct {
+	Page int `tranquility:"query,page"`
+}
+
+var TestIntBindHandler = func(ctx context.Context, in *PageParams) (*Buzz, error) {
+	return &Buzz{Greeting: strconv.Itoa(in.Page)}, nil
+}
+
+func TestHandlerBindsPathAndQuery(t *testing.T) {
+	mux := http.NewServeMux()
+
+	handler := tranquility.NewHandler(TestBindHandler, tranquility.WithCodec[ListParams, Buzz](&MyCodec[ListParams, Buzz]{}))
+
+	mux.Handle("GET /items/{id}", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42?limit=10", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `{"greeting":"42:10"}`, w.Body.String())
+}
+
+func TestHandlerValidatorRejects(t *testing.T) {
+	mux := http.NewServeMux()
+
+	handler := tranquility.NewHandler(
+		TestBindHandler,
+		tranquility.WithCodec[ListParams, Buzz](&MyCodec[ListParams, Buzz]{}),
+		tranquility.WithValidator[ListParams, Buzz](func(ctx context.Context, in *ListParams) error {
+			if in.Limit == "" {
+				return tranquility.InvalidParams{Params: []string{"limit"}, Message: "limit is required"}
+			}
+			return nil
+		}),
+	)
+
+	mux.Handle("GET /items/{id}", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/items/42", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestHandlerBindsIntField(t *testing.T) {
+	mux := http.NewServeMux()
+
+	handler := tranquility.NewHandler(TestIntBindHandler, tranquility.WithCodec[PageParams, Buzz](&MyCodec[PageParams, Buzz]{}))
+
+	mux.Handle("GET /pages", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/pages?page=3", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, `{"greeting":"3"}`, w.Body.String())
+}
+
+func TestHandlerBindsIntFieldInvalidValue(t *testing.T) {
+	mux := http.NewServeMux()
+
+	handler := tranquility.NewHandler(TestIntBindHandler, tranquility.WithCodec[PageParams, Buzz](&MyCodec[PageParams, Buzz]{}))
+
+	mux.Handle("GET /pages", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/pages?page=not-a-number", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
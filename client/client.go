@@ -0,0 +1,234 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/syke99/tranquility"
+)
+
+// Client[In, Out] is the counterpart to tranquility.Handler[In, Out]: it
+// marshals In, sends it to a fixed method/path, and unmarshals Out from a
+// successful response - or a tranquility.UserError, decoded from the
+// server's ErrorEnvelope, from an unsuccessful one
+type Client[In any, Out any] struct {
+	httpClient  *http.Client
+	baseURL     string
+	method      string
+	path        string
+	codec       tranquility.Codec[Out, In]
+	contentType string
+	headerFunc  func(ctx context.Context, in *In) map[string]string
+	pathParams  func(in *In) map[string]string
+	queryParams func(in *In) url.Values
+}
+
+// MimeTyped may be implemented by a Codec passed to WithCodec to advertise
+// the MIME type its Marshal produces. Do sets the request's Content-Type
+// from this when present, so the header actually reflects the body encoding
+// instead of always assuming JSON
+type MimeTyped interface {
+	ContentType() string
+}
+
+// ClientOption configures a Client built with NewClient
+type ClientOption[In any, Out any] func(*Client[In, Out])
+
+// NewClient builds a Client that sends method requests to baseURL+path. path
+// may contain "{name}" placeholders filled in by WithPathParams
+func NewClient[In any, Out any](baseURL, method, path string, opts ...ClientOption[In, Out]) *Client[In, Out] {
+	c := &Client[In, Out]{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		method:     method,
+		path:       path,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithHTTPClient overrides the *http.Client used to send requests, in place
+// of http.DefaultClient
+func WithHTTPClient[In any, Out any](httpClient *http.Client) ClientOption[In, Out] {
+	return func(c *Client[In, Out]) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithCodec provides a Codec for custom serialization of the outgoing In and
+// incoming Out. Note the type arguments are swapped relative to the server's
+// Codec[In, Out]: Marshal encodes *In and Unmarshal decodes into *Out, which
+// is exactly Codec[Out, In]'s shape
+func WithCodec[In any, Out any](codec tranquility.Codec[Out, In]) ClientOption[In, Out] {
+	return func(c *Client[In, Out]) {
+		c.codec = codec
+	}
+}
+
+// WithContentType overrides the Content-Type header Do sends, for codecs
+// that don't implement MimeTyped. Takes precedence over a codec's own
+// MimeTyped value
+func WithContentType[In any, Out any](contentType string) ClientOption[In, Out] {
+	return func(c *Client[In, Out]) {
+		c.contentType = contentType
+	}
+}
+
+// WithRequestHeaderFunc lets you set custom request headers derived from in
+func WithRequestHeaderFunc[In any, Out any](headerFunc func(ctx context.Context, in *In) map[string]string) ClientOption[In, Out] {
+	return func(c *Client[In, Out]) {
+		c.headerFunc = headerFunc
+	}
+}
+
+// WithPathParams substitutes "{name}" placeholders in path with values
+// derived from in
+func WithPathParams[In any, Out any](pathParams func(in *In) map[string]string) ClientOption[In, Out] {
+	return func(c *Client[In, Out]) {
+		c.pathParams = pathParams
+	}
+}
+
+// WithQueryParams appends a query string derived from in
+func WithQueryParams[In any, Out any](queryParams func(in *In) url.Values) ClientOption[In, Out] {
+	return func(c *Client[In, Out]) {
+		c.queryParams = queryParams
+	}
+}
+
+// Do marshals in, sends it per the Client's configured method/baseURL/path,
+// and unmarshals the response into an Out. For GET and DELETE, in is not
+// marshalled and the request carries no body. A non-2xx response is decoded as
+// a tranquility.UserError and returned as the error - via the configured
+// codec's EnvelopeUnmarshaler when it has one, falling back to
+// tranquility.DecodeError's plain JSON otherwise
+func (c *Client[In, Out]) Do(ctx context.Context, in *In) (*Out, error) {
+	path := c.path
+
+	if c.pathParams != nil {
+		for name, value := range c.pathParams(in) {
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+		}
+	}
+
+	reqURL := c.baseURL + path
+
+	if c.queryParams != nil {
+		if q := c.queryParams(in); len(q) > 0 {
+			reqURL += "?" + q.Encode()
+		}
+	}
+
+	var body io.Reader
+
+	if c.method != http.MethodGet && c.method != http.MethodDelete {
+		data, err := c.marshalIn(in)
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", c.resolveContentType())
+
+	if c.headerFunc != nil {
+		for k, v := range c.headerFunc(ctx, in) {
+			req.Header.Set(k, v)
+		}
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		if userErr, derr := c.decodeError(data); derr == nil {
+			return nil, userErr
+		}
+
+		return nil, fmt.Errorf("tranquility: unexpected status %d: %s", res.StatusCode, string(data))
+	}
+
+	out := new(Out)
+	if err = c.unmarshalOut(data, out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// resolveContentType picks the Content-Type to send: an explicit
+// WithContentType override, then the configured codec's own MimeTyped
+// value, and only then the "application/json" default
+func (c *Client[In, Out]) resolveContentType() string {
+	if c.contentType != "" {
+		return c.contentType
+	}
+
+	if c.codec != nil {
+		if mt, ok := any(c.codec).(MimeTyped); ok {
+			return mt.ContentType()
+		}
+	}
+
+	return "application/json"
+}
+
+func (c *Client[In, Out]) marshalIn(in *In) ([]byte, error) {
+	if c.codec != nil {
+		return c.codec.Marshal(in)
+	}
+
+	return json.Marshal(in)
+}
+
+func (c *Client[In, Out]) unmarshalOut(data []byte, out *Out) error {
+	if c.codec != nil {
+		return c.codec.Unmarshal(data, out)
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// decodeError decodes a non-2xx response body into a tranquility.UserError.
+// If the configured codec implements tranquility.EnvelopeUnmarshaler, the
+// envelope is decoded in that codec's own wire format - e.g. protobuf - so a
+// Client paired with a non-JSON Handler can still reconstruct a typed error;
+// otherwise it falls back to tranquility.DecodeError's plain JSON decoding
+func (c *Client[In, Out]) decodeError(data []byte) (tranquility.UserError, error) {
+	if c.codec != nil {
+		if unmarshaler, ok := any(c.codec).(tranquility.EnvelopeUnmarshaler); ok {
+			envelope, err := unmarshaler.UnmarshalEnvelope(data)
+			if err != nil {
+				return nil, err
+			}
+
+			return tranquility.UserErrorFromEnvelope(envelope), nil
+		}
+	}
+
+	return tranquility.DecodeError(data)
+}
@@ -0,0 +1,149 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syke99/tranquility"
+	"github.com/syke99/tranquility/client"
+)
+
+type Fizz struct {
+	Language string `json:"language"`
+}
+
+type Buzz struct {
+	Greeting string `json:"greeting"`
+}
+
+var BadLanguage = errors.New("language not supported")
+
+func helloWorldHandler(ctx context.Context, in *Fizz) (*Buzz, error) {
+	if in.Language != "english" {
+		return nil, tranquility.InvalidParams{Params: []string{"language"}, Message: BadLanguage.Error()}
+	}
+
+	return &Buzz{Greeting: "hello world!"}, nil
+}
+
+func TestClientDoSuccess(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("POST /hello", tranquility.NewHandler(helloWorldHandler))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := client.NewClient[Fizz, Buzz](server.URL, http.MethodPost, "/hello")
+
+	out, err := c.Do(context.Background(), &Fizz{Language: "english"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world!", out.Greeting)
+}
+
+func TestClientDoUserError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("POST /hello", tranquility.NewHandler(helloWorldHandler))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := client.NewClient[Fizz, Buzz](server.URL, http.MethodPost, "/hello")
+
+	_, err := c.Do(context.Background(), &Fizz{Language: "latin"})
+	assert.Error(t, err)
+
+	var userErr tranquility.UserError
+	assert.True(t, errors.As(err, &userErr))
+	assert.Equal(t, "invalid_params", userErr.Code())
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Marshal(in *Fizz) ([]byte, error) {
+	return json.Marshal(in)
+}
+
+func (upperCodec) Unmarshal(data []byte, out *Buzz) error {
+	return json.Unmarshal(data, out)
+}
+
+func (upperCodec) ContentType() string {
+	return "application/x-upper"
+}
+
+func TestClientDoDecodesNonJSONErrorEnvelope(t *testing.T) {
+	mux := http.NewServeMux()
+
+	registry := tranquility.NewMarshalerRegistry[Fizz, Buzz]()
+	registry.Register("application/x-upper-proto", tranquility.ProtoCodec[Fizz, Buzz]{
+		Marshaler: func(out *Buzz) ([]byte, error) {
+			return json.Marshal(out)
+		},
+		Unmarshaler: func(data []byte, in *Fizz) error {
+			return json.Unmarshal(data, in)
+		},
+		EnvelopeMarshaler: func(envelope tranquility.ErrorEnvelope) ([]byte, error) {
+			return []byte(envelope.Code + "\n" + envelope.Error), nil
+		},
+	})
+
+	mux.Handle("POST /hello", tranquility.NewHandler(
+		helloWorldHandler,
+		tranquility.WithMarshalerRegistry[Fizz, Buzz](registry),
+	))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	upperProtoCodec := tranquility.ProtoCodec[Buzz, Fizz]{
+		Marshaler: func(in *Fizz) ([]byte, error) {
+			return json.Marshal(in)
+		},
+		Unmarshaler: func(data []byte, out *Buzz) error {
+			return json.Unmarshal(data, out)
+		},
+		EnvelopeUnmarshaler: func(data []byte) (tranquility.ErrorEnvelope, error) {
+			code, message, _ := strings.Cut(string(data), "\n")
+			return tranquility.ErrorEnvelope{Code: code, Error: message}, nil
+		},
+	}
+
+	c := client.NewClient[Fizz, Buzz](
+		server.URL, http.MethodPost, "/hello",
+		client.WithCodec[Fizz, Buzz](upperProtoCodec),
+		client.WithContentType[Fizz, Buzz]("application/x-upper-proto"),
+	)
+
+	_, err := c.Do(context.Background(), &Fizz{Language: "latin"})
+	assert.Error(t, err)
+
+	var userErr tranquility.UserError
+	assert.True(t, errors.As(err, &userErr))
+	assert.Equal(t, "invalid_params", userErr.Code())
+}
+
+func TestClientDoSendsCodecContentType(t *testing.T) {
+	var gotContentType string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /hello", func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"greeting":"hello world!"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := client.NewClient[Fizz, Buzz](server.URL, http.MethodPost, "/hello", client.WithCodec[Fizz, Buzz](upperCodec{}))
+
+	_, err := c.Do(context.Background(), &Fizz{Language: "english"})
+	assert.NoError(t, err)
+	assert.Equal(t, "application/x-upper", gotContentType)
+}
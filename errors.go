@@ -0,0 +1,167 @@
+package tranquility
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// UserError is implemented by errors that know how to present themselves to
+// API clients: a machine-readable Code, the usual Error() message, and the
+// HTTPStatus they should be reported with. Handler's ServeHTTP checks for
+// this (via errors.As, so wrapped errors are still found) and, when present,
+// responds with the canonical ErrorEnvelope instead of falling back to
+// http.Error's text/plain body
+type UserError interface {
+	error
+	Code() string
+	HTTPStatus() int
+}
+
+// ErrorEnvelope is the canonical shape RespondError marshals a UserError
+// into. Details is omitted unless the UserError also implements
+// `Details() any`
+type ErrorEnvelope struct {
+	Code    string `json:"code"`
+	Error   string `json:"error"`
+	Details any    `json:"details,omitempty"`
+}
+
+// EnvelopeMarshaler may be implemented by a Codec to marshal an
+// ErrorEnvelope into that Codec's own wire format - e.g. a protobuf Codec
+// registered on a MarshalerRegistry can implement this to encode error
+// responses as protobuf instead of JSON. Handler.ServeHTTP uses this when
+// the request's negotiated Codec implements it, falling back to plain JSON
+// otherwise
+type EnvelopeMarshaler interface {
+	MarshalEnvelope(envelope ErrorEnvelope) ([]byte, error)
+}
+
+// EnvelopeUnmarshaler may be implemented by a Codec to decode an
+// ErrorEnvelope from that Codec's own wire format, mirroring
+// EnvelopeMarshaler on the encode side. client.Client.Do uses this so a
+// non-2xx response written with a non-JSON Codec/EnvelopeMarshaler pairing -
+// e.g. protobuf - can still be reconstructed into a typed UserError instead
+// of always assuming JSON
+type EnvelopeUnmarshaler interface {
+	UnmarshalEnvelope(data []byte) (ErrorEnvelope, error)
+}
+
+// ErrorResponder is implemented by Handler. Middleware that needs to report
+// an error from outside the normal handler/validator flow - tranquility/
+// middleware.Recovery reporting a recovered panic, say - can type-assert the
+// next http.Handler in its chain against this to route the error through the
+// wrapped Handler's configured errorHandler and MarshalerRegistry, rather
+// than responding directly and bypassing that negotiation
+type ErrorResponder interface {
+	HandleError(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// RespondError unwraps err looking for a UserError and returns the status
+// and body to respond with. Errors that aren't (and don't wrap) a UserError
+// fall back to a 500 with an "internal" code
+func RespondError(err error) (int, any) {
+	var userErr UserError
+	if errors.As(err, &userErr) {
+		return userErr.HTTPStatus(), ErrorEnvelope{
+			Code:    userErr.Code(),
+			Error:   userErr.Error(),
+			Details: detailsOf(userErr),
+		}
+	}
+
+	return http.StatusInternalServerError, ErrorEnvelope{
+		Code:  "internal",
+		Error: err.Error(),
+	}
+}
+
+func detailsOf(err UserError) any {
+	if d, ok := err.(interface{ Details() any }); ok {
+		return d.Details()
+	}
+
+	return nil
+}
+
+func asUserError(err error) (UserError, bool) {
+	var userErr UserError
+	ok := errors.As(err, &userErr)
+	return userErr, ok
+}
+
+// DecodeError reconstructs a UserError from a response body previously
+// written with RespondError. Because the envelope doesn't carry an HTTP
+// status of its own (the caller already has that from the transport), the
+// returned UserError's HTTPStatus always reports 0
+func DecodeError(data []byte) (UserError, error) {
+	var envelope ErrorEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	return UserErrorFromEnvelope(envelope), nil
+}
+
+// UserErrorFromEnvelope builds a UserError from an already-decoded
+// ErrorEnvelope. It's exported for callers that decode the envelope
+// themselves via a non-JSON Codec - e.g. client.Client.Do via
+// EnvelopeUnmarshaler - and just need the resulting UserError, without
+// DecodeError's own JSON decoding step
+func UserErrorFromEnvelope(envelope ErrorEnvelope) UserError {
+	return &decodedUserError{
+		code:    envelope.Code,
+		message: envelope.Error,
+		details: envelope.Details,
+	}
+}
+
+type decodedUserError struct {
+	code    string
+	message string
+	details any
+}
+
+func (e *decodedUserError) Error() string   { return e.message }
+func (e *decodedUserError) Code() string    { return e.code }
+func (e *decodedUserError) HTTPStatus() int { return 0 }
+func (e *decodedUserError) Details() any    { return e.details }
+
+// InvalidParams reports that one or more request parameters failed
+// validation
+type InvalidParams struct {
+	Params  []string
+	Message string
+}
+
+func (e InvalidParams) Error() string   { return e.Message }
+func (e InvalidParams) Code() string    { return "invalid_params" }
+func (e InvalidParams) HTTPStatus() int { return http.StatusBadRequest }
+func (e InvalidParams) Details() any    { return e.Params }
+
+// NotFound reports that the requested resource doesn't exist
+type NotFound struct{}
+
+func (NotFound) Error() string   { return "not found" }
+func (NotFound) Code() string    { return "not_found" }
+func (NotFound) HTTPStatus() int { return http.StatusNotFound }
+
+// AuthRequired reports that the request needs authentication that wasn't
+// provided
+type AuthRequired struct{}
+
+func (AuthRequired) Error() string   { return "authentication required" }
+func (AuthRequired) Code() string    { return "auth_required" }
+func (AuthRequired) HTTPStatus() int { return http.StatusUnauthorized }
+
+// InternalFailure reports an unexpected server-side failure, tagged with an
+// ID so it can be correlated with server logs without leaking internals to
+// the client
+type InternalFailure struct {
+	ID string
+}
+
+func (e InternalFailure) Error() string   { return "internal failure" }
+func (e InternalFailure) Code() string    { return "internal_failure" }
+func (e InternalFailure) HTTPStatus() int { return http.StatusInternalServerError }
+func (e InternalFailure) Details() any    { return e.ID }
@@ -0,0 +1,100 @@
+package tranquility_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syke99/tranquility"
+)
+
+var TestUserErrorHandler = func(ctx context.Context, in *Fizz) (*Buzz, error) {
+	return nil, tranquility.NotFound{}
+}
+
+func TestHandlerUserError(t *testing.T) {
+	mux := http.NewServeMux()
+
+	handler := tranquility.NewHandler(
+		TestUserErrorHandler,
+		tranquility.WithCodec[Fizz, Buzz](TestCodec),
+	)
+
+	mux.Handle("GET /hello", handler)
+
+	fizz := &Fizz{Language: "english"}
+	b, err := json.Marshal(fizz)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", bytes.NewReader(b))
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+	resBytes, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	decoded, err := tranquility.DecodeError(resBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, "not_found", decoded.Code())
+	assert.Equal(t, "not found", decoded.Error())
+}
+
+func TestHandlerUserErrorUsesEnvelopeMarshaler(t *testing.T) {
+	mux := http.NewServeMux()
+
+	registry := tranquility.NewMarshalerRegistry[Fizz, Buzz]()
+	registry.Register("application/x-upper-proto", tranquility.ProtoCodec[Fizz, Buzz]{
+		Marshaler: func(out *Buzz) ([]byte, error) {
+			return json.Marshal(out)
+		},
+		Unmarshaler: func(data []byte, in *Fizz) error {
+			return json.Unmarshal(data, in)
+		},
+		EnvelopeMarshaler: func(envelope tranquility.ErrorEnvelope) ([]byte, error) {
+			b, err := json.Marshal(envelope)
+			if err != nil {
+				return nil, err
+			}
+			return bytes.ToUpper(b), nil
+		},
+	})
+
+	handler := tranquility.NewHandler(
+		TestUserErrorHandler,
+		tranquility.WithMarshalerRegistry[Fizz, Buzz](registry),
+	)
+
+	mux.Handle("GET /hello", handler)
+
+	fizz := &Fizz{Language: "english"}
+	b, err := json.Marshal(fizz)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", bytes.NewReader(b))
+	req.Header.Set("Accept", "application/x-upper-proto")
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, "application/x-upper-proto", res.Header.Get("Content-Type"))
+
+	resBytes, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"CODE":"NOT_FOUND","ERROR":"NOT FOUND"}`, string(resBytes))
+}
@@ -12,14 +12,22 @@ import (
 // added. The structure of the incoming request body gets unmarshalled to In,
 // and Out will get marshalled to the response body. Because of this, the default
 // method for marshalling and unmarshalling using tranquility is via json. However,
-// a Codec may be provided to implement custom serialization. If you need access to
-// the entire incoming request, you can find it in the injected context using the
+// a MarshalerRegistry may be provided to pick a Codec per request based on
+// Content-Type/Accept, letting a single Handler serve multiple wire formats. Fields
+// of In tagged `tranquility:"path,id"`, `tranquility:"query,limit"`, or
+// `tranquility:"header,X-Request-ID"` are additionally filled from the request's path
+// values, query parameters, and headers before the body is unmarshalled, so GET/DELETE
+// routes that carry no body still have a populated In. If you need access to the
+// entire incoming request, you can find it in the injected context using the
 // "request" key
 type Handler[In any, Out any] struct {
 	handler      func(ctx context.Context, in *In) (*Out, error)
 	headerFunc   func(ctx context.Context, in *In, out *Out) map[string]string
-	codec        Codec[In, Out]
+	registry     *MarshalerRegistry[In, Out]
 	errorHandler func(ctx context.Context, err error) (int, error)
+	middleware   []Middleware
+	binder       Binder
+	validator    func(ctx context.Context, in *In) error
 }
 
 func NewHandler[In any, Out any](handler func(ctx context.Context, in *In) (*Out, error), opts ...func(Handler *Handler[In, Out])) http.Handler {
@@ -31,7 +39,13 @@ func NewHandler[In any, Out any](handler func(ctx context.Context, in *In) (*Out
 		opt(h)
 	}
 
-	return h
+	var wrapped http.Handler = h
+
+	for i := len(h.middleware) - 1; i >= 0; i-- {
+		wrapped = h.middleware[i](wrapped)
+	}
+
+	return wrapped
 }
 
 func (h *Handler[In, Out]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -41,48 +55,56 @@ func (h *Handler[In, Out]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	in := new(In)
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "unable to read request body", http.StatusBadRequest)
-		return
+	binder := h.binder
+	if binder == nil {
+		binder = defaultBinder{}
 	}
 
-	if h.codec != nil {
-		err = h.codec.Unmarshal(body, in)
-	} else {
-		err = json.Unmarshal(body, in)
+	if err := binder.Bind(r, in); err != nil {
+		h.respondErr(w, r, ctx, err)
+		return
 	}
 
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "unable to unmarshal request body", http.StatusBadRequest)
+		http.Error(w, "unable to read request body", http.StatusBadRequest)
 		return
 	}
 
-	out, err := h.handler(ctx, in)
-	if err != nil {
-		if h.errorHandler != nil {
-			resCode := http.StatusInternalServerError
-			resErr := err
+	_, decoder := h.registry.decoderFor(r)
 
-			resCode, resErr = h.errorHandler(ctx, err)
-			http.Error(w, resErr.Error(), resCode)
+	if len(body) > 0 {
+		err = decoder.Unmarshal(body, in)
+		if err != nil {
+			http.Error(w, "unable to unmarshal request body", http.StatusBadRequest)
 			return
 		}
 	}
 
-	var resultBytes []byte
+	if h.validator != nil {
+		if err := h.validator(ctx, in); err != nil {
+			h.respondErr(w, r, ctx, err)
+			return
+		}
+	}
 
-	if h.codec != nil {
-		resultBytes, err = h.codec.Marshal(out)
-	} else {
-		resultBytes, err = json.Marshal(out)
+	out, err := h.handler(ctx, in)
+	if err != nil {
+		h.respondErr(w, r, ctx, err)
+		return
 	}
 
+	encMime, encoder := h.registry.encoderFor(r)
+
+	resultBytes, err := encoder.Marshal(out)
 	if err != nil {
 		http.Error(w, "unable to marshal response", http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", encMime)
+	w.Header().Add("Vary", "Accept")
+
 	if h.headerFunc != nil {
 		customHeaders := h.headerFunc(ctx, in, out)
 
@@ -93,3 +115,66 @@ func (h *Handler[In, Out]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	_, _ = w.Write(resultBytes)
 }
+
+// HandleError routes err through this Handler's configured errorHandler and
+// MarshalerRegistry exactly as ServeHTTP would for a handler- or
+// validator-returned error. It's exported so middleware that recovers from
+// something outside that normal flow - a panic, say - can report it through
+// the same negotiated codec/error pipeline as this Handler instead of
+// reimplementing one; see ErrorResponder and tranquility/middleware.Recovery
+func (h *Handler[In, Out]) HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := context.WithValue(context.Background(), "request", r)
+
+	h.respondErr(w, r, ctx, err)
+}
+
+// respondErr runs err through the configured errorHandler (if any) and
+// writes the result: as a RespondError envelope when it's a UserError,
+// falling back to http.Error otherwise
+func (h *Handler[In, Out]) respondErr(w http.ResponseWriter, r *http.Request, ctx context.Context, err error) {
+	resCode := http.StatusInternalServerError
+	resErr := err
+
+	if h.errorHandler != nil {
+		resCode, resErr = h.errorHandler(ctx, err)
+	}
+
+	if userErr, ok := asUserError(resErr); ok {
+		h.writeErrorEnvelope(w, r, userErr)
+		return
+	}
+
+	http.Error(w, resErr.Error(), resCode)
+}
+
+// writeErrorEnvelope responds with the canonical ErrorEnvelope for a
+// UserError. If the Codec negotiated for this request (via the Handler's
+// MarshalerRegistry) implements EnvelopeMarshaler, the envelope is encoded
+// in that Codec's wire format - e.g. protobuf - so error responses stay
+// consistent with success responses; otherwise it falls back to plain JSON
+func (h *Handler[In, Out]) writeErrorEnvelope(w http.ResponseWriter, r *http.Request, userErr UserError) {
+	status, body := RespondError(userErr)
+
+	envelope, _ := body.(ErrorEnvelope)
+
+	mimeType, encoder := h.registry.encoderFor(r)
+
+	if marshaler, ok := encoder.(EnvelopeMarshaler); ok {
+		if payload, err := marshaler.MarshalEnvelope(envelope); err == nil {
+			w.Header().Set("Content-Type", mimeType)
+			w.WriteHeader(status)
+			_, _ = w.Write(payload)
+			return
+		}
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		http.Error(w, userErr.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(payload)
+}
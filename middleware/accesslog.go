@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/syke99/tranquility"
+)
+
+// AccessLog returns a Middleware that emits one structured log record per
+// request via logger, recording the method, path, status, response size,
+// and duration
+func AccessLog(logger *slog.Logger) tranquility.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			wrapped := &responseWriterWrapper{ResponseWriter: w}
+
+			next.ServeHTTP(wrapped, r)
+
+			logger.Info("request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", wrapped.status,
+				"bytes", wrapped.bytes,
+				"duration", time.Since(start),
+			)
+		})
+	}
+}
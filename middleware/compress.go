@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/syke99/tranquility"
+)
+
+// Compress returns a Middleware that gzips the response body when the
+// client's Accept-Encoding allows it. The wrapped writer flushes the gzip
+// stream (and the underlying connection, when flushable) on every Write, so
+// it plays nicely with a StreamingHandler further down the chain, which
+// needs each chunk delivered to the client as soon as it's produced
+func Compress(level int) tranquility.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gz, err := gzip.NewWriterLevel(w, level)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			defer gz.Close()
+
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, gz: gz}, r)
+		})
+	}
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.gz.Write(b)
+	w.flush()
+	return n, err
+}
+
+func (w *gzipResponseWriter) Flush() {
+	w.flush()
+}
+
+func (w *gzipResponseWriter) flush() {
+	_ = w.gz.Flush()
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,195 @@
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syke99/tranquility"
+	"github.com/syke99/tranquility/middleware"
+)
+
+type Fizz struct {
+	Language string `json:"language"`
+}
+
+type Buzz struct {
+	Greeting string `json:"greeting"`
+}
+
+func TestRecoveryConvertsPanicToEnvelope(t *testing.T) {
+	handler := middleware.Recovery()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+	resBytes, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	var envelope tranquility.ErrorEnvelope
+	assert.NoError(t, json.Unmarshal(resBytes, &envelope))
+	assert.Equal(t, "internal_failure", envelope.Code)
+	assert.NotEqual(t, "boom", envelope.Details)
+}
+
+func TestRecoveryLogsPanicWithCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := middleware.Recovery(middleware.WithLogger(logger))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	resBytes, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+
+	var envelope tranquility.ErrorEnvelope
+	assert.NoError(t, json.Unmarshal(resBytes, &envelope))
+
+	logLine := buf.String()
+	assert.Contains(t, logLine, "panic recovered")
+	assert.Contains(t, logLine, "panic=boom")
+	assert.Contains(t, logLine, "correlation_id="+envelope.Details.(string))
+}
+
+func TestRecoveryRoutesThroughHandlerErrorPipeline(t *testing.T) {
+	var errorHandlerCalled bool
+
+	h := tranquility.NewHandler(
+		func(ctx context.Context, in *Fizz) (*Buzz, error) {
+			panic("boom")
+		},
+		tranquility.WithErrorHandler[Fizz, Buzz](func(ctx context.Context, err error) (int, error) {
+			errorHandlerCalled = true
+			return http.StatusTeapot, tranquility.NotFound{}
+		}),
+		tranquility.WithMiddleware[Fizz, Buzz](middleware.Recovery()),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.True(t, errorHandlerCalled)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestCORSPreflight(t *testing.T) {
+	handler := middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next handler should not run for a preflight request")
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "https://example.com", res.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", res.Header.Get("Access-Control-Allow-Methods"))
+}
+
+func TestCompressGzipsWhenAccepted(t *testing.T) {
+	handler := middleware.Compress(gzip.DefaultCompression)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello world"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+
+	gz, err := gzip.NewReader(res.Body)
+	assert.NoError(t, err)
+	defer gz.Close()
+
+	body, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(body))
+}
+
+func TestCompressSkipsWithoutAcceptEncoding(t *testing.T) {
+	handler := middleware.Compress(gzip.DefaultCompression)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello world"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Empty(t, res.Header.Get("Content-Encoding"))
+
+	body, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", string(body))
+}
+
+func TestAccessLogCapturesStatusAndBytes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := middleware.AccessLog(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hi"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	logLine := buf.String()
+	assert.Contains(t, logLine, "method=GET")
+	assert.Contains(t, logLine, "path=/widgets")
+	assert.Contains(t, logLine, "status=201")
+	assert.Contains(t, logLine, "bytes=2")
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/syke99/tranquility"
+)
+
+// RecoveryOption configures Recovery
+type RecoveryOption func(*recoveryConfig)
+
+type recoveryConfig struct {
+	logger  *slog.Logger
+	onPanic func(ctx context.Context, recovered any) tranquility.UserError
+}
+
+// WithLogger overrides the *slog.Logger the default onPanic uses to record a
+// recovered panic and its stack trace, in place of slog.Default()
+func WithLogger(logger *slog.Logger) RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.logger = logger
+	}
+}
+
+// WithPanicHandler replaces the default panic-to-UserError conversion
+// entirely. The default logs the recovered value and a stack trace
+// server-side and returns a tranquility.InternalFailure carrying a generated
+// correlation ID (never the recovered value itself, which may contain
+// internals that shouldn't reach the client); a custom onPanic takes over
+// that responsibility completely, including any logging
+func WithPanicHandler(f func(ctx context.Context, recovered any) tranquility.UserError) RecoveryOption {
+	return func(c *recoveryConfig) {
+		c.onPanic = f
+	}
+}
+
+// Recovery returns a Middleware that recovers panics in the wrapped handler
+// and reports them as a tranquility.UserError instead of letting net/http's
+// default behavior tear down the connection with no body. The error is
+// routed through the wrapped Handler's configured errorHandler and
+// MarshalerRegistry via tranquility.ErrorResponder, exactly as a handler- or
+// validator-returned error would be - so Recovery must be the innermost
+// Middleware wrapping a tranquility.Handler for that routing to apply; if the
+// next handler in the chain doesn't implement ErrorResponder, Recovery falls
+// back to a plain JSON envelope
+func Recovery(opts ...RecoveryOption) tranquility.Middleware {
+	cfg := &recoveryConfig{
+		logger: slog.Default(),
+	}
+
+	cfg.onPanic = func(ctx context.Context, recovered any) tranquility.UserError {
+		id := newCorrelationID()
+
+		cfg.logger.ErrorContext(ctx, "panic recovered",
+			"correlation_id", id,
+			"panic", fmt.Sprintf("%v", recovered),
+			"stack", string(debug.Stack()),
+		)
+
+		return tranquility.InternalFailure{ID: id}
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					userErr := cfg.onPanic(r.Context(), recovered)
+
+					if responder, ok := next.(tranquility.ErrorResponder); ok {
+						responder.HandleError(w, r, userErr)
+						return
+					}
+
+					writeFallbackEnvelope(w, userErr)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeFallbackEnvelope is used only when the wrapped handler doesn't
+// implement tranquility.ErrorResponder, so there's no negotiated codec to
+// route the error through
+func writeFallbackEnvelope(w http.ResponseWriter, userErr tranquility.UserError) {
+	status, body := tranquility.RespondError(userErr)
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(payload)
+}
+
+func newCorrelationID() string {
+	var b [16]byte
+
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("unidentified-%p", &b)
+	}
+
+	return hex.EncodeToString(b[:])
+}
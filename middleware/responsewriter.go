@@ -0,0 +1,34 @@
+package middleware
+
+import "net/http"
+
+// responseWriterWrapper captures the status code and byte count written
+// through it so middleware further up the chain (e.g. AccessLog) can observe
+// them, which a bare http.ResponseWriter has no way to expose
+type responseWriterWrapper struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseWriterWrapper) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriterWrapper) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+func (w *responseWriterWrapper) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
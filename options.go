@@ -1,13 +1,60 @@
 package tranquility
 
-import "context"
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware wraps an http.Handler to add cross-cutting behavior (logging,
+// compression, panic recovery, CORS, etc.) around it. WithMiddleware applies
+// a chain of these around a Handler's ServeHTTP
+type Middleware func(http.Handler) http.Handler
+
+// WithBinder overrides how a Handler populates In from the path, query, and
+// header parts of the request, in place of the default `tranquility:"..."`
+// struct-tag binder. Binding always runs before the body is unmarshalled
+func WithBinder[In any, Out any](binder Binder) func(*Handler[In, Out]) {
+	return func(h *Handler[In, Out]) {
+		h.binder = binder
+	}
+}
+
+// WithValidator runs after binding and body unmarshalling and before the
+// handler func. A returned error flows through errorHandler exactly like an
+// error from the handler func itself
+func WithValidator[In any, Out any](validator func(ctx context.Context, in *In) error) func(*Handler[In, Out]) {
+	return func(h *Handler[In, Out]) {
+		h.validator = validator
+	}
+}
+
+// WithMiddleware wraps a Handler's ServeHTTP in the given Middleware chain,
+// applied in order: the first Middleware is outermost, so it sees the
+// request first and the response last
+func WithMiddleware[In any, Out any](middleware ...Middleware) func(*Handler[In, Out]) {
+	return func(h *Handler[In, Out]) {
+		h.middleware = append(h.middleware, middleware...)
+	}
+}
 
 // WithCodec allows you to provide a codec for your tranquility
 // handler to be able to inject custom serialization of your
-// incoming request body and outgoing response body
+// incoming request body and outgoing response body. It registers the codec
+// under "application/json" and makes it the registry's default; to serve
+// more than one wire format from the same Handler, use WithMarshalerRegistry
+// instead
 func WithCodec[In any, Out any](codec Codec[In, Out]) func(*Handler[In, Out]) {
 	return func(h *Handler[In, Out]) {
-		h.codec = codec
+		h.registry = NewMarshalerRegistry[In, Out]().Register("application/json", codec)
+	}
+}
+
+// WithMarshalerRegistry lets a single Handler serve multiple wire formats by
+// picking a Codec per request: the decoder is chosen by Content-Type and the
+// encoder by q-value parsing over Accept
+func WithMarshalerRegistry[In any, Out any](registry *MarshalerRegistry[In, Out]) func(*Handler[In, Out]) {
+	return func(h *Handler[In, Out]) {
+		h.registry = registry
 	}
 }
 
@@ -28,3 +75,39 @@ func WithErrorHandler[In any, Out any](errorHandler func(ctx context.Context, er
 		h.errorHandler = errorHandler
 	}
 }
+
+// WithStreamCodec allows you to provide a codec for your StreamingHandler
+// to be able to inject custom serialization of the incoming request body
+// and the Out messages pushed through send
+func WithStreamCodec[In any, Out any](codec Codec[In, Out]) func(*StreamingHandler[In, Out]) {
+	return func(h *StreamingHandler[In, Out]) {
+		h.codec = codec
+	}
+}
+
+// WithStreamErrorHandler allows you to inject custom error handling into
+// your StreamingHandler. Because the response is already being streamed by
+// the time an error can occur, the status code this returns is informational
+// only; the error itself is written as a terminal frame in the stream
+func WithStreamErrorHandler[In any, Out any](errorHandler func(ctx context.Context, err error) (int, error)) func(*StreamingHandler[In, Out]) {
+	return func(h *StreamingHandler[In, Out]) {
+		h.errorHandler = errorHandler
+	}
+}
+
+// WithStreamMode selects the framing a StreamingHandler uses when writing
+// messages to the response: ModeNDJSON (the default) or ModeSSE
+func WithStreamMode[In any, Out any](mode StreamMode) func(*StreamingHandler[In, Out]) {
+	return func(h *StreamingHandler[In, Out]) {
+		h.streamMode = mode
+	}
+}
+
+// WithSSEEventFunc lets you name the SSE "event:" field on a per-message basis
+// when a StreamingHandler is configured with ModeSSE. Returning an empty
+// string omits the event line for that message
+func WithSSEEventFunc[In any, Out any](sseEventFunc func(out *Out) string) func(*StreamingHandler[In, Out]) {
+	return func(h *StreamingHandler[In, Out]) {
+		h.sseEventFunc = sseEventFunc
+	}
+}
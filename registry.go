@@ -0,0 +1,248 @@
+package tranquility
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarshalerRegistry lets a single Handler serve multiple wire formats by
+// keying a Codec to the MIME type used to select it: Content-Type picks the
+// decoder for the request body, and q-value parsing over Accept picks the
+// encoder for the response body
+type MarshalerRegistry[In any, Out any] struct {
+	codecs      map[string]Codec[In, Out]
+	defaultMime string
+}
+
+// NewMarshalerRegistry returns an empty MarshalerRegistry. Use Register to
+// add codecs for the MIME types your Handler should support
+func NewMarshalerRegistry[In any, Out any]() *MarshalerRegistry[In, Out] {
+	return &MarshalerRegistry[In, Out]{
+		codecs: make(map[string]Codec[In, Out]),
+	}
+}
+
+// Register associates a Codec with a MIME type. The first MIME type
+// registered becomes the registry's default unless overridden with
+// WithDefaultMime
+func (m *MarshalerRegistry[In, Out]) Register(mimeType string, codec Codec[In, Out]) *MarshalerRegistry[In, Out] {
+	if m.codecs == nil {
+		m.codecs = make(map[string]Codec[In, Out])
+	}
+
+	if m.defaultMime == "" {
+		m.defaultMime = mimeType
+	}
+
+	m.codecs[mimeType] = codec
+
+	return m
+}
+
+// WithDefaultMime overrides the MIME type used to pick a codec when a
+// request doesn't specify one, or specifies one the registry doesn't
+// recognize
+func (m *MarshalerRegistry[In, Out]) WithDefaultMime(mimeType string) *MarshalerRegistry[In, Out] {
+	m.defaultMime = mimeType
+	return m
+}
+
+func (m *MarshalerRegistry[In, Out]) lookup(mimeType string) (Codec[In, Out], bool) {
+	if m == nil {
+		return nil, false
+	}
+
+	c, ok := m.codecs[mimeType]
+	return c, ok
+}
+
+// decoderFor picks the Codec to use for unmarshalling the request body,
+// based on the Content-Type header, falling back to the registry's default
+// and then to JSON
+func (m *MarshalerRegistry[In, Out]) decoderFor(r *http.Request) (string, Codec[In, Out]) {
+	return m.resolve(contentTypeOf(r.Header.Get("Content-Type")))
+}
+
+// encoderFor picks the Codec to use for marshalling the response body,
+// running q-value parsing over the Accept header, falling back to the
+// registry's default and then to JSON
+func (m *MarshalerRegistry[In, Out]) encoderFor(r *http.Request) (string, Codec[In, Out]) {
+	for _, mimeType := range parseAccept(r.Header.Get("Accept")) {
+		if codec, ok := m.lookup(mimeType); ok {
+			return mimeType, codec
+		}
+	}
+
+	return m.resolve("")
+}
+
+func (m *MarshalerRegistry[In, Out]) resolve(mimeType string) (string, Codec[In, Out]) {
+	if mimeType != "" {
+		if codec, ok := m.lookup(mimeType); ok {
+			return mimeType, codec
+		}
+	}
+
+	if m != nil && m.defaultMime != "" {
+		if codec, ok := m.lookup(m.defaultMime); ok {
+			return m.defaultMime, codec
+		}
+	}
+
+	return "application/json", jsonCodec[In, Out]{}
+}
+
+func contentTypeOf(header string) string {
+	mimeType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return strings.TrimSpace(strings.SplitN(header, ";", 2)[0])
+	}
+
+	return mimeType
+}
+
+// parseAccept returns the MIME types from an Accept header ordered from
+// most to least preferred, per RFC 7231 q-value weighting
+func parseAccept(header string) []string {
+	if header == "" {
+		return nil
+	}
+
+	type weighted struct {
+		mimeType string
+		q        float64
+	}
+
+	var parsed []weighted
+
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+
+		mimeType := strings.TrimSpace(fields[0])
+		if mimeType == "" || mimeType == "*/*" {
+			continue
+		}
+
+		q := 1.0
+
+		for _, param := range fields[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.TrimSpace(kv[0]) == "q" {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		parsed = append(parsed, weighted{mimeType: mimeType, q: q})
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return parsed[i].q > parsed[j].q
+	})
+
+	mimeTypes := make([]string, 0, len(parsed))
+	for _, p := range parsed {
+		mimeTypes = append(mimeTypes, p.mimeType)
+	}
+
+	return mimeTypes
+}
+
+// jsonCodec is the built-in Codec used for application/json, and as the
+// fallback when no registry (or no matching entry) is configured
+type jsonCodec[In any, Out any] struct{}
+
+func (jsonCodec[In, Out]) Marshal(out *Out) ([]byte, error) {
+	return json.Marshal(out)
+}
+
+func (jsonCodec[In, Out]) Unmarshal(data []byte, in *In) error {
+	return json.Unmarshal(data, in)
+}
+
+// FormCodec is the built-in, decode-only Codec for
+// application/x-www-form-urlencoded. It fills In's string fields from the
+// form body using `schema:"name"` struct tags, mirroring gorilla/schema
+type FormCodec[In any, Out any] struct{}
+
+func (FormCodec[In, Out]) Marshal(out *Out) ([]byte, error) {
+	return nil, errors.New("tranquility: application/x-www-form-urlencoded does not support encoding responses")
+}
+
+func (FormCodec[In, Out]) Unmarshal(data []byte, in *In) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(in).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("schema")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		raw := values.Get(tag)
+		if raw == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		if field.CanSet() && field.Kind() == reflect.String {
+			field.SetString(raw)
+		}
+	}
+
+	return nil
+}
+
+// ProtoCodec is a thin Codec adapter for application/x-protobuf. It carries
+// no serialization logic of its own; wire it up with jsonpb/proto.Marshal
+// (or any other protobuf runtime) via Marshaler/Unmarshaler and Register it
+// on a MarshalerRegistry. Setting EnvelopeMarshaler additionally lets error
+// responses for requests negotiated onto this Codec be encoded as protobuf
+// too, via the EnvelopeMarshaler interface, instead of falling back to JSON.
+// Setting EnvelopeUnmarshaler does the symmetric job on the decode side: a
+// client.Client configured with this same ProtoCodec (type-swapped, per
+// client.WithCodec) can then reconstruct a typed UserError from a protobuf
+// error envelope via EnvelopeUnmarshaler, instead of assuming JSON
+type ProtoCodec[In any, Out any] struct {
+	Marshaler           func(out *Out) ([]byte, error)
+	Unmarshaler         func(data []byte, in *In) error
+	EnvelopeMarshaler   func(envelope ErrorEnvelope) ([]byte, error)
+	EnvelopeUnmarshaler func(data []byte) (ErrorEnvelope, error)
+}
+
+func (p ProtoCodec[In, Out]) Marshal(out *Out) ([]byte, error) {
+	return p.Marshaler(out)
+}
+
+func (p ProtoCodec[In, Out]) MarshalEnvelope(envelope ErrorEnvelope) ([]byte, error) {
+	if p.EnvelopeMarshaler == nil {
+		return nil, errors.New("tranquility: ProtoCodec has no EnvelopeMarshaler configured")
+	}
+
+	return p.EnvelopeMarshaler(envelope)
+}
+
+func (p ProtoCodec[In, Out]) UnmarshalEnvelope(data []byte) (ErrorEnvelope, error) {
+	if p.EnvelopeUnmarshaler == nil {
+		return ErrorEnvelope{}, errors.New("tranquility: ProtoCodec has no EnvelopeUnmarshaler configured")
+	}
+
+	return p.EnvelopeUnmarshaler(data)
+}
+
+func (p ProtoCodec[In, Out]) Unmarshal(data []byte, in *In) error {
+	return p.Unmarshaler(data, in)
+}
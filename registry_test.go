@@ -0,0 +1,58 @@
+package tranquility_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syke99/tranquility"
+)
+
+type UpperCodec[In any, Out any] struct{}
+
+func (c UpperCodec[In, Out]) Marshal(out *Out) ([]byte, error) {
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.ToUpper(b), nil
+}
+
+func (c UpperCodec[In, Out]) Unmarshal(data []byte, in *In) error {
+	return json.Unmarshal(data, in)
+}
+
+func TestHandlerMarshalerRegistry(t *testing.T) {
+	mux := http.NewServeMux()
+
+	registry := tranquility.NewMarshalerRegistry[Fizz, Buzz]()
+	registry.Register("application/json", TestCodec)
+	registry.Register("application/x-upper", UpperCodec[Fizz, Buzz]{})
+
+	handler := tranquility.NewHandler(
+		TestHandler,
+		tranquility.WithMarshalerRegistry[Fizz, Buzz](registry),
+	)
+
+	mux.Handle("GET /hello", handler)
+
+	fizz := &Fizz{Language: "english"}
+	b, err := json.Marshal(fizz)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/hello", bytes.NewReader(b))
+	req.Header.Set("Accept", "application/x-upper")
+
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, "application/x-upper", res.Header.Get("Content-Type"))
+	assert.Equal(t, `{"GREETING":"HELLO WORLD!"}`, w.Body.String())
+}
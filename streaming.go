@@ -0,0 +1,164 @@
+package tranquility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StreamMode selects the framing tranquility uses when writing a StreamingHandler's
+// output messages to the response body.
+type StreamMode int
+
+const (
+	// ModeNDJSON writes each Out as a newline-delimited JSON (or codec-marshalled)
+	// message: codec.Marshal(out) followed by "\n".
+	ModeNDJSON StreamMode = iota
+	// ModeSSE writes each Out as a Server-Sent Event: "data: " + codec.Marshal(out) + "\n\n",
+	// optionally preceded by an "event: <name>" line when a WithSSEEventFunc is configured.
+	ModeSSE
+)
+
+// StreamingHandler is the streaming counterpart to Handler. Instead of returning a
+// single Out, the handler func is given a send callback it can invoke any number of
+// times to push messages to the client as they become available. The incoming request
+// body is decoded into In with the configured codec (JSON by default); unlike Handler,
+// no path/query/header binding, validator, or MarshalerRegistry negotiation is applied.
+// If you need access to the entire incoming
+// request, you can find it in the injected context using the "request" key
+type StreamingHandler[In any, Out any] struct {
+	handler      func(ctx context.Context, in *In, send func(*Out) error) error
+	codec        Codec[In, Out]
+	errorHandler func(ctx context.Context, err error) (int, error)
+	streamMode   StreamMode
+	sseEventFunc func(out *Out) string
+}
+
+// NewStreamingHandler constructs a StreamingHandler from a streaming handler func and
+// any number of options. The returned http.Handler can be registered with an
+// http.ServeMux just like a Handler built with NewHandler.
+func NewStreamingHandler[In any, Out any](handler func(ctx context.Context, in *In, send func(*Out) error) error, opts ...func(*StreamingHandler[In, Out])) http.Handler {
+	h := &StreamingHandler[In, Out]{
+		handler: handler,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *StreamingHandler[In, Out]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.WithValue(r.Context(), "request", r)
+
+	in := new(In)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "unable to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(body) > 0 {
+		if h.codec != nil {
+			err = h.codec.Unmarshal(body, in)
+		} else {
+			err = json.Unmarshal(body, in)
+		}
+
+		if err != nil {
+			http.Error(w, "unable to unmarshal request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	flusher, canFlush := w.(http.Flusher)
+
+	switch h.streamMode {
+	case ModeSSE:
+		w.Header().Set("Content-Type", "text/event-stream")
+	default:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Transfer-Encoding", "chunked")
+	w.WriteHeader(http.StatusOK)
+
+	if canFlush {
+		flusher.Flush()
+	}
+
+	send := func(out *Out) error {
+		var data []byte
+		var merr error
+
+		if h.codec != nil {
+			data, merr = h.codec.Marshal(out)
+		} else {
+			data, merr = json.Marshal(out)
+		}
+
+		if merr != nil {
+			return merr
+		}
+
+		switch h.streamMode {
+		case ModeSSE:
+			if h.sseEventFunc != nil {
+				if event := h.sseEventFunc(out); event != "" {
+					fmt.Fprintf(w, "event: %s\n", event)
+				}
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		default:
+			w.Write(data)
+			w.Write([]byte("\n"))
+		}
+
+		if canFlush {
+			flusher.Flush()
+		}
+
+		// stop producing once the client has gone away
+		select {
+		case <-r.Context().Done():
+			return r.Context().Err()
+		default:
+			return nil
+		}
+	}
+
+	if err = h.handler(ctx, in, send); err != nil {
+		h.writeErrorFrame(w, ctx, err)
+
+		if canFlush {
+			flusher.Flush()
+		}
+	}
+}
+
+// writeErrorFrame routes a mid-stream error through the configured errorHandler and
+// writes it as a terminal frame. Headers (and likely a 200 status) have already been
+// sent by the time a stream can fail, so the error can't be surfaced with http.Error;
+// instead it's encoded in-band using the handler's stream mode.
+func (h *StreamingHandler[In, Out]) writeErrorFrame(w http.ResponseWriter, ctx context.Context, err error) {
+	resErr := err
+
+	if h.errorHandler != nil {
+		_, resErr = h.errorHandler(ctx, err)
+	}
+
+	payload, merr := json.Marshal(map[string]string{"error": resErr.Error()})
+	if merr != nil {
+		payload = []byte(`{"error":"internal error"}`)
+	}
+
+	switch h.streamMode {
+	case ModeSSE:
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", payload)
+	default:
+		fmt.Fprintf(w, "%s\n", payload)
+	}
+}
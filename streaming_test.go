@@ -0,0 +1,79 @@
+package tranquility_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/syke99/tranquility"
+)
+
+var TestStreamingHandler = func(ctx context.Context, in *Fizz, send func(*Buzz) error) error {
+	if in.Language != "english" {
+		return BadLanguage
+	}
+
+	for _, word := range []string{"hello", "world"} {
+		if err := send(&Buzz{Greeting: word}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestNewStreamingHandler(t *testing.T) {
+	handler := tranquility.NewStreamingHandler(TestStreamingHandler)
+
+	assert.NotNil(t, handler)
+}
+
+func TestStreamingHandlerNDJSON(t *testing.T) {
+	mux := http.NewServeMux()
+
+	handler := tranquility.NewStreamingHandler(
+		TestStreamingHandler,
+		tranquility.WithStreamCodec[Fizz, Buzz](TestCodec),
+	)
+
+	mux.Handle("GET /stream", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", strings.NewReader(`{"language":"english"}`))
+
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+	assert.Equal(t, "{\"greeting\":\"hello\"}\n{\"greeting\":\"world\"}\n", w.Body.String())
+}
+
+func TestStreamingHandlerSSE(t *testing.T) {
+	mux := http.NewServeMux()
+
+	handler := tranquility.NewStreamingHandler(
+		TestStreamingHandler,
+		tranquility.WithStreamCodec[Fizz, Buzz](TestCodec),
+		tranquility.WithStreamMode[Fizz, Buzz](tranquility.ModeSSE),
+	)
+
+	mux.Handle("GET /stream", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", strings.NewReader(`{"language":"english"}`))
+
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	assert.Equal(t, "data: {\"greeting\":\"hello\"}\n\ndata: {\"greeting\":\"world\"}\n\n", w.Body.String())
+}